@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// cacheHashSize bounds hashExe to the first/last N bytes, so hashing a
+// large executable doesn't dominate over just parsing it.
+const cacheHashSize = 1 << 20
+
+// cacheKey identifies a cached Parse result.
+type cacheKey struct {
+	ExeHash    string
+	Filter     string
+	Context    int
+	MaxMatches int
+}
+
+func newCacheKey(opts Options) (cacheKey, error) {
+	exeHash, err := hashExe(opts.Exe)
+	if err != nil {
+		return cacheKey{}, err
+	}
+	return cacheKey{
+		ExeHash:    exeHash,
+		Filter:     opts.Filter.String(),
+		Context:    opts.Context,
+		MaxMatches: opts.MaxSymbols,
+	}, nil
+}
+
+func (key cacheKey) path(dir string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d", key.ExeHash, key.Filter, key.Context, key.MaxMatches)))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// hashExe hashes the size plus the first and last cacheHashSize bytes of exe.
+func hashExe(exe string) (string, error) {
+	f, err := os.Open(exe)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n", info.Size())
+
+	head := make([]byte, cacheHashSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	h.Write(head[:n])
+
+	if info.Size() > cacheHashSize {
+		tail := make([]byte, cacheHashSize)
+		if _, err := f.ReadAt(tail, info.Size()-cacheHashSize); err != nil {
+			return "", err
+		}
+		h.Write(tail)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// defaultCacheDir returns os.UserCacheDir()/lensm. It does not create the
+// directory; storeCache does that on first write.
+func defaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lensm"), nil
+}
+
+// loadCache reports ok=false, with no error, on any kind of cache miss.
+func loadCache(dir string, opts Options) (out *Output, ok bool) {
+	if dir == "" {
+		return nil, false
+	}
+	key, err := newCacheKey(opts)
+	if err != nil {
+		return nil, false
+	}
+
+	f, err := os.Open(key.path(dir))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	out = &Output{}
+	if err := json.NewDecoder(f).Decode(out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// storeCache writes out to the cache under dir, creating dir if needed.
+func storeCache(dir string, opts Options, out *Output) error {
+	if dir == "" {
+		return nil
+	}
+	key, err := newCacheKey(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := json.NewEncoder(tmp)
+	if err := enc.Encode(out); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), key.path(dir))
+}
+
+// ParseCached behaves like Parse, but consults an on-disk cache under dir
+// first, keyed by the executable's content hash and the parse options. This
+// only helps repeated invocations against an unchanged binary (e.g.
+// scripting -format against a build that hasn't changed) — every rebuild
+// changes the hash, so the auto-refresh path would always miss and calls
+// refreshParse instead, see refreshExe. Pass dir == "" to disable caching.
+func ParseCached(dir string, opts Options) (*Output, error) {
+	if out, ok := loadCache(dir, opts); ok {
+		return out, nil
+	}
+
+	out, err := Parse(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storeCache(dir, opts, out); err != nil {
+		fmt.Fprintf(os.Stderr, "lensm: writing cache: %v\n", err)
+	}
+
+	return out, nil
+}
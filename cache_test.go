@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, dir string, size int, fill byte) string {
+	t.Helper()
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = fill
+	}
+	path := filepath.Join(dir, "exe")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHashExeDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTempFile(t, dir, 1024, 'a')
+
+	h1, err := hashExe(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := hashExe(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Fatalf("hashExe not deterministic: %q != %q", h1, h2)
+	}
+}
+
+func TestHashExeDiffersOnContent(t *testing.T) {
+	dirA, dirB := t.TempDir(), t.TempDir()
+	pathA := writeTempFile(t, dirA, 1024, 'a')
+	pathB := writeTempFile(t, dirB, 1024, 'b')
+
+	hA, err := hashExe(pathA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hB, err := hashExe(pathB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hA == hB {
+		t.Fatalf("hashExe gave the same hash for different content")
+	}
+}
+
+func TestHashExeLargerThanWindow(t *testing.T) {
+	dir := t.TempDir()
+	// Bigger than cacheHashSize on both ends, to exercise the head+tail path.
+	path := writeTempFile(t, dir, 2*cacheHashSize+1024, 'c')
+
+	if _, err := hashExe(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheKeyPath(t *testing.T) {
+	dir := t.TempDir()
+
+	key := cacheKey{ExeHash: "abc", Filter: "Foo.*", Context: 3, MaxMatches: 10}
+	p1 := key.path(dir)
+	p2 := key.path(dir)
+	if p1 != p2 {
+		t.Fatalf("cacheKey.path not deterministic: %q != %q", p1, p2)
+	}
+
+	other := key
+	other.Filter = "Bar.*"
+	if other.path(dir) == p1 {
+		t.Fatalf("different filters produced the same cache path")
+	}
+}
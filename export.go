@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ExportText writes out in the format used by -format=text and -text.
+func ExportText(w io.Writer, out *Output) error {
+	for _, symbol := range out.Matches {
+		fmt.Fprintf(w, "\n\n// func %v (%v)\n", symbol.Name, symbol.File)
+		for _, ix := range symbol.Code {
+			if ix.RefPC != 0 {
+				fmt.Fprintf(w, "    %-60v %v@%3v %08x --> %08x\n", ix.Text, ix.File, ix.Line, ix.PC, ix.RefPC)
+			} else {
+				fmt.Fprintf(w, "    %-60v %v@%3v %08x\n", ix.Text, ix.File, ix.Line, ix.PC)
+			}
+		}
+
+		fmt.Fprintf(w, "// CONTEXT\n")
+		for _, source := range symbol.Source {
+			fmt.Fprintf(w, "// FILE  %v\n", source.File)
+			for i, block := range source.Blocks {
+				if i > 0 {
+					fmt.Fprintf(w, "...:\n")
+				}
+				for line, text := range block.Lines {
+					fmt.Fprintf(w, "%3d:  %v\n", block.From+line, text)
+				}
+			}
+		}
+	}
+	fmt.Fprintln(w, "MORE", out.More)
+	return nil
+}
+
+// ExportJSON writes out as machine-readable JSON.
+func ExportJSON(w io.Writer, out *Output) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// ExportHTML writes out as a self-contained HTML page, one section per
+// matched symbol with asm and source side by side.
+func ExportHTML(w io.Writer, out *Output) error {
+	fmt.Fprint(w, htmlHeader)
+	for _, symbol := range out.Matches {
+		if err := writeHTMLSymbol(w, &symbol); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(w, htmlFooter)
+	return nil
+}
+
+func writeHTMLSymbol(w io.Writer, symbol *Match) error {
+	fmt.Fprintf(w, "<section class=\"symbol\">\n")
+	fmt.Fprintf(w, "<h2>%s <small>%s</small></h2>\n", html.EscapeString(symbol.Name), html.EscapeString(symbol.File))
+	fmt.Fprintf(w, "<div class=\"columns\">\n")
+
+	fmt.Fprintf(w, "<pre class=\"asm\">\n")
+	for _, ix := range symbol.Code {
+		id := fmt.Sprintf("pc-%08x", ix.PC)
+		line := fmt.Sprintf("%-60v %v@%3v %08x", html.EscapeString(ix.Text), html.EscapeString(ix.File), ix.Line, ix.PC)
+		if ix.RefPC != 0 {
+			line += fmt.Sprintf(" --&gt; <a href=\"#pc-%08x\">%08x</a>", ix.RefPC, ix.RefPC)
+		}
+		fmt.Fprintf(w, "<span id=\"%s\">%s</span>\n", id, line)
+	}
+	fmt.Fprintf(w, "</pre>\n")
+
+	fmt.Fprintf(w, "<pre class=\"source\">\n")
+	for _, source := range symbol.Source {
+		fmt.Fprintf(w, "<div class=\"file\">%s</div>\n", html.EscapeString(source.File))
+		for i, block := range source.Blocks {
+			if i > 0 {
+				fmt.Fprintf(w, "<div class=\"skip\">...</div>\n")
+			}
+			for line, text := range block.Lines {
+				fmt.Fprintf(w, "<span>%3d:  %s</span>\n", block.From+line, highlightGoLine(text))
+			}
+		}
+	}
+	fmt.Fprintf(w, "</pre>\n")
+
+	fmt.Fprintf(w, "</div>\n</section>\n")
+	return nil
+}
+
+// highlightPattern tags Go comments, string/char literals, and keywords so
+// highlightGoLine can wrap them in a <span>; everything else passes through
+// escaped as plain text.
+var highlightPattern = regexp.MustCompile(
+	`(?P<comment>//.*$)` +
+		"|" + `(?P<string>"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `|'(?:[^'\\]|\\.)*')` +
+		`|(?P<keyword>\b(?:break|case|chan|const|continue|default|defer|else|fallthrough|for|func|go|goto|if|import|interface|map|package|range|return|select|struct|switch|type|var)\b)`,
+)
+
+// highlightGoLine renders line as HTML with basic Go syntax highlighting,
+// escaping everything it doesn't recognize.
+func highlightGoLine(line string) string {
+	names := highlightPattern.SubexpNames()
+	var out strings.Builder
+	last := 0
+	for _, m := range highlightPattern.FindAllStringSubmatchIndex(line, -1) {
+		start, end := m[0], m[1]
+		out.WriteString(html.EscapeString(line[last:start]))
+
+		class := ""
+		for i, name := range names {
+			if name != "" && m[2*i] != -1 {
+				class = name
+				break
+			}
+		}
+
+		fmt.Fprintf(&out, "<span class=\"%s\">%s</span>", class, html.EscapeString(line[start:end]))
+		last = end
+	}
+	out.WriteString(html.EscapeString(line[last:]))
+	return out.String()
+}
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>lensm</title>
+<style>
+body { font-family: sans-serif; }
+.symbol { margin-bottom: 2em; }
+.columns { display: flex; gap: 1em; }
+.asm, .source { flex: 1; overflow-x: auto; background: #f5f5f5; padding: 0.5em; }
+.asm > span, .source > span { display: block; white-space: pre; }
+.source .file { font-weight: bold; }
+.source .skip { color: #888; }
+.source .keyword { color: #a626a4; font-weight: bold; }
+.source .string { color: #50a14f; }
+.source .comment { color: #888; font-style: italic; }
+a { color: #06c; text-decoration: none; }
+a:hover { text-decoration: underline; }
+</style>
+</head>
+<body>
+`
+
+const htmlFooter = `</body>
+</html>
+`
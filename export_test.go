@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlightGoLineEscapesText(t *testing.T) {
+	got := highlightGoLine(`x := a < b && b > c`)
+	if strings.Contains(got, "<") || strings.Contains(got, ">") {
+		t.Fatalf("highlightGoLine left raw < or > in output: %q", got)
+	}
+	if !strings.Contains(got, "&lt;") || !strings.Contains(got, "&gt;") {
+		t.Fatalf("highlightGoLine did not escape < or >: %q", got)
+	}
+}
+
+func TestHighlightGoLineEscapesStringLiteral(t *testing.T) {
+	got := highlightGoLine(`s := "<script>"`)
+	if strings.Contains(got, "<script>") {
+		t.Fatalf("highlightGoLine left a raw string literal unescaped: %q", got)
+	}
+	if !strings.Contains(got, `class="string"`) {
+		t.Fatalf("highlightGoLine did not tag the string literal: %q", got)
+	}
+	if !strings.Contains(got, "&lt;script&gt;") {
+		t.Fatalf("highlightGoLine did not escape the string literal's contents: %q", got)
+	}
+}
+
+func TestHighlightGoLineTagsKeyword(t *testing.T) {
+	got := highlightGoLine(`if err != nil {`)
+	if !strings.Contains(got, `class="keyword"`) {
+		t.Fatalf("highlightGoLine did not tag the keyword: %q", got)
+	}
+}
+
+func TestHighlightGoLineTagsComment(t *testing.T) {
+	got := highlightGoLine(`return x // x < y & done`)
+	if !strings.Contains(got, `class="comment"`) {
+		t.Fatalf("highlightGoLine did not tag the comment: %q", got)
+	}
+	if !strings.Contains(got, "&lt;") || !strings.Contains(got, "&amp;") {
+		t.Fatalf("highlightGoLine did not escape comment contents: %q", got)
+	}
+}
+
+func TestHighlightGoLineEmpty(t *testing.T) {
+	if got := highlightGoLine(""); got != "" {
+		t.Fatalf("highlightGoLine(%q) = %q, want empty", "", got)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+
+	"gioui.org/font/gofont"
+	"gioui.org/font/opentype"
+	"gioui.org/text"
+)
+
+// systemFontPaths are platform-appropriate font files with wider Unicode
+// coverage than gofont; the first one found is used as a fallback.
+var systemFontPaths = map[string][]string{
+	"darwin": {
+		"/System/Library/Fonts/AppleSDGothicNeo.ttc",
+		"/System/Library/Fonts/PingFang.ttc",
+		"/Library/Fonts/Arial Unicode.ttf",
+	},
+	"linux": {
+		"/usr/share/fonts/truetype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+	},
+	"windows": {
+		`C:\Windows\Fonts\msgothic.ttc`,
+		`C:\Windows\Fonts\meiryo.ttc`,
+		`C:\Windows\Fonts\arial.ttf`,
+	},
+}
+
+// loadFontCollection returns gofont plus a system font fallback; path
+// overrides the platform default when non-empty, and falls back to
+// gofont-only, silently, when no system font is available.
+func loadFontCollection(path string) []text.FontFace {
+	collection := gofont.Collection()
+
+	paths := systemFontPaths[runtime.GOOS]
+	if path != "" {
+		paths = []string{path}
+	}
+
+	for _, path := range paths {
+		faces, err := parseFontFile(path)
+		if err != nil {
+			continue
+		}
+		return append(collection, faces...)
+	}
+
+	return collection
+}
+
+// parseFontFile reads path fully into memory before parsing: the sfnt
+// parser underlying ParseCollectionReaderAt reads font tables lazily as
+// glyphs are shaped, well after this function returns, so an *os.File
+// closed here would fail on first use.
+func parseFontFile(path string) ([]text.FontFace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return opentype.ParseCollectionReaderAt(bytes.NewReader(data))
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pcRange is the address span of one symbol, as reported by the binary's
+// symbol table.
+type pcRange struct {
+	Lo, Hi uint64
+}
+
+// indexSymbols lists the PC range of every defined symbol in exe matching
+// filter that `nm -S` reports with a size (symbols nm prints without a size
+// are skipped), by reading the symbol table without disassembling
+// anything. This is orders of magnitude cheaper than the objdump/DWARF walk
+// Parse does per matched symbol, which is what makes it usable on every
+// auto-refresh.
+func indexSymbols(exe string, filter *regexp.Regexp) (map[string]pcRange, error) {
+	cmd := exec.Command("nm", "-S", "--defined-only", "--numeric-sort", exe)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("nm %v: %w", exe, err)
+	}
+
+	ranges := make(map[string]pcRange)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// "<addr> <size> <type> <name>"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		name := fields[3]
+		if !filter.MatchString(name) {
+			continue
+		}
+		lo, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseUint(fields[1], 16, 64)
+		if err != nil {
+			continue
+		}
+		ranges[name] = pcRange{Lo: lo, Hi: lo + size}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// matchPCRange returns the span covered by match's instructions, or
+// ok=false if match has no code to measure.
+func matchPCRange(match *Match) (r pcRange, ok bool) {
+	if len(match.Code) == 0 {
+		return pcRange{}, false
+	}
+	r.Lo, r.Hi = match.Code[0].PC, match.Code[0].PC
+	for _, ix := range match.Code[1:] {
+		if ix.PC < r.Lo {
+			r.Lo = ix.PC
+		}
+		if ix.PC > r.Hi {
+			r.Hi = ix.PC
+		}
+	}
+	return r, true
+}
+
+// exactNameFilter returns a regexp matching exactly the given symbol names.
+func exactNameFilter(names []string) *regexp.Regexp {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return regexp.MustCompile("^(?:" + strings.Join(quoted, "|") + ")$")
+}
+
+// refreshParse re-parses opts.Exe for an auto-refresh, reusing matches from
+// prev whose PC range hasn't moved since the last parse, so a rebuild only
+// pays disassembly cost for the symbols that actually changed.
+//
+// It first lists every matching symbol's PC range cheaply via indexSymbols;
+// anything whose range is unchanged from prev is reused as-is, and Parse is
+// only invoked, with its filter narrowed to the remaining names, for
+// symbols that are new or whose range moved. If the cheap index can't be
+// read (e.g. nm isn't on PATH, or the binary's format has no symbol table
+// nm understands), it falls back to a plain Parse so refresh still works,
+// just without the fast path.
+func refreshParse(prev *Output, opts Options) (*Output, error) {
+	ranges, err := indexSymbols(opts.Exe, opts.Filter)
+	if err != nil {
+		return Parse(opts)
+	}
+
+	reused := make(map[string]Match, len(ranges))
+	if prev != nil {
+		for _, match := range prev.Matches {
+			r, ok := matchPCRange(&match)
+			if !ok {
+				continue
+			}
+			if want, ok := ranges[match.Name]; ok && want == r {
+				reused[match.Name] = match
+				delete(ranges, match.Name)
+			}
+		}
+	}
+
+	if len(ranges) > 0 {
+		changedNames := make([]string, 0, len(ranges))
+		for name := range ranges {
+			changedNames = append(changedNames, name)
+		}
+		sort.Strings(changedNames)
+
+		changedOpts := opts
+		changedOpts.Filter = exactNameFilter(changedNames)
+		changed, err := Parse(changedOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range changed.Matches {
+			reused[match.Name] = match
+		}
+	}
+
+	ordered := make([]string, 0, len(reused))
+	for name := range reused {
+		ordered = append(ordered, name)
+	}
+	sort.Strings(ordered)
+
+	out := &Output{}
+	for _, name := range ordered {
+		if opts.MaxSymbols > 0 && len(out.Matches) >= opts.MaxSymbols {
+			out.More = true
+			break
+		}
+		out.Matches = append(out.Matches, reused[name])
+	}
+	return out, nil
+}
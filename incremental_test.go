@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestExactNameFilter(t *testing.T) {
+	re := exactNameFilter([]string{"main.Foo", "main.Bar.Baz"})
+
+	for _, name := range []string{"main.Foo", "main.Bar.Baz"} {
+		if !re.MatchString(name) {
+			t.Fatalf("exactNameFilter didn't match %q", name)
+		}
+	}
+	for _, name := range []string{"main.FooBar", "xmain.Foo", "main.Foo2"} {
+		if re.MatchString(name) {
+			t.Fatalf("exactNameFilter matched unrelated name %q", name)
+		}
+	}
+}
+
+// buildTestExe compiles a tiny C program with a couple of named, sized
+// functions, so indexSymbols has something real to read the symbol table
+// of.
+func buildTestExe(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("cc"); err != nil {
+		t.Skip("cc not available")
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "main.c")
+	exe := filepath.Join(dir, "exe")
+	content := `
+int lensmtest_add(int a, int b) { return a + b; }
+int lensmtest_sub(int a, int b) { return a - b; }
+int main(void) { return lensmtest_add(1, lensmtest_sub(2, 1)); }
+`
+	if err := os.WriteFile(src, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("cc", "-O0", "-o", exe, src)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("cc failed: %v\n%s", err, out)
+	}
+	return exe
+}
+
+func TestIndexSymbols(t *testing.T) {
+	exe := buildTestExe(t)
+
+	ranges, err := indexSymbols(exe, regexp.MustCompile(`^lensmtest_`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"lensmtest_add", "lensmtest_sub"} {
+		r, ok := ranges[name]
+		if !ok {
+			t.Fatalf("indexSymbols missed %q, got %v", name, ranges)
+		}
+		if r.Hi <= r.Lo {
+			t.Fatalf("indexSymbols gave a non-positive size for %q: %+v", name, r)
+		}
+	}
+	if _, ok := ranges["main"]; ok {
+		t.Fatalf("indexSymbols returned %q, which the filter should have excluded", "main")
+	}
+}
+
+func TestIndexSymbolsDeterministic(t *testing.T) {
+	exe := buildTestExe(t)
+	filter := regexp.MustCompile(`^lensmtest_`)
+
+	r1, err := indexSymbols(exe, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := indexSymbols(exe, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range r1 {
+		got, ok := r2[name]
+		if !ok || got != want {
+			t.Fatalf("indexSymbols not deterministic for %q: %+v != %+v", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListBoxSetActiveClamps(t *testing.T) {
+	var box ListBox
+
+	box.setActive(2, 5)
+	if box.active != 2 {
+		t.Fatalf("active = %d, want 2", box.active)
+	}
+
+	box.setActive(-1, 5)
+	if box.active != 0 {
+		t.Fatalf("active = %d, want 0 (clamped low)", box.active)
+	}
+
+	box.setActive(10, 5)
+	if box.active != 4 {
+		t.Fatalf("active = %d, want 4 (clamped high)", box.active)
+	}
+
+	box.setActive(0, 0)
+	if box.active != 0 {
+		t.Fatalf("active = %d, want 0 for empty list", box.active)
+	}
+}
+
+func TestListBoxMoveActive(t *testing.T) {
+	var box ListBox
+	box.setActive(2, 5)
+
+	box.moveActive(1, 5)
+	if box.active != 3 {
+		t.Fatalf("active = %d, want 3", box.active)
+	}
+
+	box.moveActive(-2, 5)
+	if box.active != 1 {
+		t.Fatalf("active = %d, want 1", box.active)
+	}
+
+	box.moveActive(-10, 5)
+	if box.active != 0 {
+		t.Fatalf("active = %d, want 0 (clamped)", box.active)
+	}
+
+	box.moveActive(10, 5)
+	if box.active != 4 {
+		t.Fatalf("active = %d, want 4 (clamped)", box.active)
+	}
+}
+
+func TestListBoxTypeaheadBuffer(t *testing.T) {
+	var box ListBox
+	box.typeahead = "foo"
+	box.typeaheadAt = time.Now()
+
+	if got := box.typeaheadBuffer(); got != "foo" {
+		t.Fatalf("typeaheadBuffer() = %q, want %q", got, "foo")
+	}
+
+	box.typeaheadAt = time.Now().Add(-typeaheadTimeout - time.Second)
+	if got := box.typeaheadBuffer(); got != "" {
+		t.Fatalf("typeaheadBuffer() = %q, want \"\" once idle", got)
+	}
+}
+
+func TestListBoxScrollPosition(t *testing.T) {
+	var box ListBox
+
+	box.setActive(5, 10)
+	box.scroll.Position.First = 0
+	box.scroll.Position.Count = 3
+
+	pos := box.scrollPosition(10)
+	if pos.First != 5 {
+		t.Fatalf("First = %d, want 5 (scrolled to keep active visible)", pos.First)
+	}
+
+	box.scroll.Position.First = 4
+	box.scroll.Position.Count = 3
+	pos = box.scrollPosition(10)
+	if pos.First != 4 {
+		t.Fatalf("First = %d, want 4 (already visible, position untouched)", pos.First)
+	}
+}
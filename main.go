@@ -8,9 +8,11 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"gioui.org/app"
-	"gioui.org/font/gofont"
 	"gioui.org/io/key"
 	"gioui.org/io/system"
 	"gioui.org/layout"
@@ -45,10 +47,14 @@ func Fibonacci(n int) int {
 var _ = Fibonacci(0)
 
 func main() {
-	text := flag.Bool("text", false, "show text output")
+	text := flag.Bool("text", false, "show text output (alias for -format=text)")
+	format := flag.String("format", "", "output format: text, json, html (default: open the GUI)")
 	filter := flag.String("filter", "", "filter the symbol by regexp")
 	context := flag.Int("context", 3, "source line context")
 	maxMatches := flag.Int("max-matches", 10, "maximum number of matches to parse")
+	fontPath := flag.String("font", "", "path to a system font file to use for wider Unicode coverage")
+	noCache := flag.Bool("no-cache", false, "disable the on-disk parse cache")
+	cacheDirFlag := flag.String("cache-dir", "", "override the parse cache directory (default: os.UserCacheDir()/lensm)")
 	flag.Parse()
 	exename := flag.Arg(0)
 
@@ -63,45 +69,53 @@ func main() {
 		panic(err)
 	}
 
-	out, err := Parse(Options{
+	cacheDir := *cacheDirFlag
+	if !*noCache && cacheDir == "" {
+		cacheDir, err = defaultCacheDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "lensm: disabling cache: %v\n", err)
+		}
+	}
+	if *noCache {
+		cacheDir = ""
+	}
+
+	opts := Options{
 		Exe:        exename,
 		Filter:     re,
 		Context:    *context,
 		MaxSymbols: *maxMatches,
-	})
+	}
+
+	out, err := ParseCached(cacheDir, opts)
 	if err != nil {
 		panic(err)
 	}
 
-	if *text {
-		for _, symbol := range out.Matches {
-			fmt.Printf("\n\n// func %v (%v)\n", symbol.Name, symbol.File)
-			for _, ix := range symbol.Code {
-				if ix.RefPC != 0 {
-					fmt.Printf("    %-60v %v@%3v %08x --> %08x\n", ix.Text, ix.File, ix.Line, ix.PC, ix.RefPC)
-				} else {
-					fmt.Printf("    %-60v %v@%3v %08x\n", ix.Text, ix.File, ix.Line, ix.PC)
-				}
-			}
+	if *text && *format == "" {
+		*format = "text"
+	}
 
-			fmt.Printf("// CONTEXT\n")
-			for _, source := range symbol.Source {
-				fmt.Printf("// FILE  %v\n", source.File)
-				for i, block := range source.Blocks {
-					if i > 0 {
-						fmt.Printf("...:\n")
-					}
-					for line, text := range block.Lines {
-						fmt.Printf("%3d:  %v\n", block.From+line, text)
-					}
-				}
-			}
+	if *format != "" {
+		var exportErr error
+		switch *format {
+		case "text":
+			exportErr = ExportText(os.Stdout, out)
+		case "json":
+			exportErr = ExportJSON(os.Stdout, out)
+		case "html":
+			exportErr = ExportHTML(os.Stdout, out)
+		default:
+			fmt.Fprintf(os.Stderr, "unknown -format %q, want text, json or html\n", *format)
+			os.Exit(1)
+		}
+		if exportErr != nil {
+			panic(exportErr)
 		}
-		fmt.Println("MORE", out.More)
 		os.Exit(0)
 	}
 
-	ui := NewUI()
+	ui := NewUI(*fontPath)
 	ui.Output = out
 
 	// This creates a new application window and starts the UI.
@@ -110,6 +124,9 @@ func main() {
 			app.Title("lensm"),
 			app.Size(unit.Dp(1400), unit.Dp(900)),
 		)
+
+		go watchExe(w, ui, exename, opts)
+
 		if err := ui.Run(w); err != nil {
 			log.Println(err)
 			os.Exit(1)
@@ -128,15 +145,36 @@ type UI struct {
 
 	Filter   widget.Editor
 	Output   *Output
-	Matches  widget.List
+	Matches  ListBox
 	Selected *Match
 	MatchUI  MatchUIState
+
+	// mu guards Output, Selected, ParseError and AutoRefresh, which can be
+	// read or swapped in from the watcher goroutine while Layout is running.
+	mu         sync.Mutex
+	ParseError error
+}
+
+// setParseError is safe to call from the watcher goroutine.
+func (ui *UI) setParseError(err error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.ParseError = err
+}
+
+// autoRefresh reports the Auto Refresh checkbox state. It's safe to call
+// from the watcher goroutine.
+func (ui *UI) autoRefresh() bool {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return ui.AutoRefresh.Value
 }
 
-func NewUI() *UI {
+// NewUI creates a new UI; fontPath overrides the platform-default system font.
+func NewUI(fontPath string) *UI {
 	ui := &UI{}
-	ui.Theme = material.NewTheme(gofont.Collection())
-	ui.Matches.List.Axis = layout.Vertical
+	ui.Theme = material.NewTheme(loadFontCollection(fontPath))
+	ui.Matches.scroll.Axis = layout.Vertical
 
 	ui.Filter.SetText("gioui.org.*decode")
 	ui.Filter.SingleLine = true
@@ -162,6 +200,9 @@ func (ui *UI) Run(w *app.Window) error {
 }
 
 func (ui *UI) Layout(gtx layout.Context) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
 	if ui.Selected == nil && len(ui.Output.Matches) > 0 {
 		ui.selectMatch(&ui.Output.Matches[0])
 	}
@@ -196,6 +237,12 @@ func (ui *UI) Layout(gtx layout.Context) {
 						body1.MaxLines = 1
 						return body1.Layout(gtx)
 					}
+					if ui.ParseError != nil {
+						body1 := material.Body1(ui.Theme, ui.ParseError.Error())
+						body1.Color = color.NRGBA{R: 0xC0, A: 0xFF}
+						body1.MaxLines = 1
+						return body1.Layout(gtx)
+					}
 					body1 := material.Body1(ui.Theme, "ok")
 					body1.MaxLines = 1
 					return body1.Layout(gtx)
@@ -242,61 +289,155 @@ func (ui *UI) Layout(gtx layout.Context) {
 	)
 }
 
+const matchesListTag = "matches-list"
+
+// typeaheadTimeout resets the typeahead buffer after this much idle time.
+const typeaheadTimeout = 750 * time.Millisecond
+
 type ListBox struct {
 	focused bool
 	active  int
 	scroll  widget.List
+
+	typeahead   string
+	typeaheadAt time.Time
 }
 
 func (ui *UI) layoutMatches(gtx layout.Context) layout.Dimensions {
 	defer clip.Rect{Max: gtx.Constraints.Min}.Push(gtx.Ops).Pop()
 
-	key.InputOp{
-		Tag:  123,
-		Keys: key.NameUpArrow + "|" + key.NameDownArrow,
-	}.Add(gtx.Ops)
-
 	n := len(ui.Output.Matches)
 	if ui.Output.More {
 		n += 1
 	}
+	ui.Matches.setActive(ui.Matches.active, n)
 
 	for i := range ui.Output.Matches {
 		match := &ui.Output.Matches[i]
 		for match.Select.Clicked() {
+			ui.Matches.active = i
 			ui.selectMatch(match)
 		}
 	}
 
-	var focusOffset int
-	for _, ev := range gtx.Events(123) {
-		fmt.Printf("%#v\n", ev)
-		if ev, ok := ev.(key.Event); ok {
-			if ev.State != key.Press {
-				continue
+	// Only steal the keyboard when the filter editor doesn't have it, so
+	// arrow keys always do something useful.
+	if !ui.Filter.Focused() {
+		key.FocusOp{Tag: matchesListTag}.Add(gtx.Ops)
+	}
+	key.InputOp{
+		Tag: matchesListTag,
+		Keys: key.NameUpArrow + "|" + key.NameDownArrow + "|" +
+			key.NamePageUp + "|" + key.NamePageDown + "|" +
+			key.NameHomeArrow + "|" + key.NameEndArrow + "|" +
+			key.NameReturn + "|" + key.NameDeleteBackward,
+	}.Add(gtx.Ops)
+
+	const page = 20
+	for _, e := range gtx.Events(matchesListTag) {
+		if e, ok := e.(key.FocusEvent); ok {
+			ui.Matches.focused = e.Focus
+			continue
+		}
+		ev, ok := e.(key.Event)
+		if !ok || ev.State != key.Press {
+			continue
+		}
+
+		switch ev.Name {
+		case key.NameUpArrow:
+			ui.Matches.moveActive(-1, n)
+		case key.NameDownArrow:
+			ui.Matches.moveActive(1, n)
+		case key.NamePageUp:
+			ui.Matches.moveActive(-page, n)
+		case key.NamePageDown:
+			ui.Matches.moveActive(page, n)
+		case key.NameHomeArrow:
+			ui.Matches.setActive(0, n)
+		case key.NameEndArrow:
+			ui.Matches.setActive(n-1, n)
+		case key.NameReturn:
+			if ui.Matches.active >= 0 && ui.Matches.active < len(ui.Output.Matches) {
+				ui.selectMatch(&ui.Output.Matches[ui.Matches.active])
 			}
-			switch ev.Name {
-			case key.NameUpArrow:
-				focusOffset--
-			case key.NameDownArrow:
-				focusOffset++
+		case key.NameDeleteBackward:
+			ui.Matches.typeahead = ""
+		default:
+			name := string(ev.Name)
+			if len([]rune(name)) == 1 {
+				ui.Matches.typeahead = ui.Matches.typeaheadBuffer() + strings.ToLower(name)
+				ui.Matches.typeaheadAt = gtx.Now
+				ui.Matches.jumpToTypeahead(ui)
 			}
 		}
 	}
-	if focusOffset != 0 {
-		fmt.Println("focus offset changed")
-	}
 
-	return material.List(ui.Theme, &ui.Matches).Layout(gtx, n,
+	list := material.List(ui.Theme, &ui.Matches.scroll)
+	list.Position = ui.Matches.scrollPosition(n)
+	dims := list.Layout(gtx, n,
 		func(gtx layout.Context, index int) layout.Dimensions {
 			if index >= len(ui.Output.Matches) {
 				return material.Body2(ui.Theme, "... too many matches ...").Layout(gtx)
 			}
-			return ui.layoutMatch(gtx, &ui.Output.Matches[index])
+			return ui.layoutMatch(gtx, index, &ui.Output.Matches[index])
 		})
+	ui.Matches.scroll.Position = list.Position
+	return dims
+}
+
+// typeaheadBuffer returns "" once idle for longer than typeaheadTimeout.
+func (box *ListBox) typeaheadBuffer() string {
+	if time.Since(box.typeaheadAt) > typeaheadTimeout {
+		return ""
+	}
+	return box.typeahead
+}
+
+// jumpToTypeahead moves active to the next match containing the buffer.
+func (box *ListBox) jumpToTypeahead(ui *UI) {
+	if box.typeahead == "" {
+		return
+	}
+	n := len(ui.Output.Matches)
+	for i := 0; i < n; i++ {
+		index := (box.active + i) % n
+		if strings.Contains(strings.ToLower(ui.Output.Matches[index].Name), box.typeahead) {
+			box.setActive(index, n)
+			return
+		}
+	}
 }
 
-func (ui *UI) layoutMatch(gtx layout.Context, match *Match) layout.Dimensions {
+func (box *ListBox) moveActive(delta, n int) {
+	box.setActive(box.active+delta, n)
+}
+
+func (box *ListBox) setActive(index, n int) {
+	if n <= 0 {
+		box.active = 0
+		return
+	}
+	switch {
+	case index < 0:
+		index = 0
+	case index >= n:
+		index = n - 1
+	}
+	box.active = index
+}
+
+// scrollPosition keeps the active row visible without fighting manual scroll.
+func (box *ListBox) scrollPosition(n int) layout.Position {
+	pos := box.scroll.Position
+	if n > 0 && (box.active < pos.First || box.active >= pos.First+pos.Count) {
+		pos.First = box.active
+		pos.Offset = 0
+	}
+	return pos
+}
+
+func (ui *UI) layoutMatch(gtx layout.Context, index int, match *Match) layout.Dimensions {
 	return material.Clickable(gtx, &match.Select, func(gtx layout.Context) layout.Dimensions {
 		style := material.Body2(ui.Theme, match.Name)
 		style.MaxLines = 1
@@ -304,6 +445,9 @@ func (ui *UI) layoutMatch(gtx layout.Context, match *Match) layout.Dimensions {
 		if match == ui.Selected {
 			style.Font.Weight = text.Heavy
 		}
+		if index == ui.Matches.active {
+			paint.FillShape(gtx.Ops, SecondaryBackground, clip.Rect{Max: gtx.Constraints.Max}.Op())
+		}
 		tgtx := gtx
 		tgtx.Constraints.Max.X = 100000
 		dims := style.Layout(tgtx) // layout.UniformInset(unit.Dp(8)).Layout(gtx, style.Layout)
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"gioui.org/app"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchExe watches exename for changes and, while Auto Refresh is checked,
+// re-parses it with opts whenever the file is modified, swapping the result
+// into ui.Output. It debounces
+// bursts of writes (e.g. from `go build`) so a single save only triggers
+// one reparse.
+//
+// It watches the containing directory rather than exename itself: fsnotify
+// watches are bound to the inode, and `go build` replaces the binary with a
+// rename, which would otherwise silently kill the watch after the first
+// rebuild.
+//
+// watchExe returns at once if the watcher fails to start, and otherwise
+// blocks until the watcher's channels close; it's meant to be run in its
+// own goroutine for the lifetime of the window.
+func watchExe(w *app.Window, ui *UI, exename string, opts Options) {
+	dir := filepath.Dir(exename)
+	base := filepath.Base(exename)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		ui.setParseError(fmt.Errorf("watch %v: %w", exename, err))
+		w.Invalidate()
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(dir); err != nil {
+		ui.setParseError(fmt.Errorf("watch %v: %w", exename, err))
+		w.Invalidate()
+		return
+	}
+
+	const debounce = 250 * time.Millisecond
+	var timer *time.Timer
+	pending := make(chan struct{}, 1)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != base {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if timer == nil {
+				timer = time.AfterFunc(debounce, func() {
+					select {
+					case pending <- struct{}{}:
+					default:
+					}
+				})
+			} else {
+				timer.Reset(debounce)
+			}
+
+		case <-pending:
+			if !ui.autoRefresh() {
+				continue
+			}
+			refreshExe(w, ui, exename, opts)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			ui.setParseError(err)
+			w.Invalidate()
+		}
+	}
+}
+
+// refreshExe re-parses exename and, on success, swaps the new output into
+// ui.Output, re-resolving the previously selected symbol by name if it's
+// still present.
+//
+// It calls refreshParse rather than ParseCached: ParseCached's cache is
+// keyed by the executable's content hash, so a rebuild (the only thing
+// that triggers a refresh) always misses it. refreshParse instead reuses
+// matches from the previous Output whose PC range is unchanged, so a
+// rebuild only pays disassembly cost for the symbols that actually moved.
+func refreshExe(w *app.Window, ui *UI, exename string, opts Options) {
+	ui.mu.Lock()
+	prev := ui.Output
+	ui.mu.Unlock()
+
+	out, err := refreshParse(prev, opts)
+	if err != nil {
+		ui.setParseError(fmt.Errorf("refresh %v: %w", exename, err))
+		w.Invalidate()
+		return
+	}
+
+	ui.mu.Lock()
+	var selectedName string
+	if ui.Selected != nil {
+		selectedName = ui.Selected.Name
+	}
+	ui.Output = out
+	ui.ParseError = nil
+	ui.Selected = nil
+	for i, match := range out.Matches {
+		if match.Name == selectedName {
+			ui.Selected = &out.Matches[i]
+			break
+		}
+	}
+	ui.mu.Unlock()
+
+	w.Invalidate()
+}